@@ -0,0 +1,126 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+const (
+	// DefaultGetHeadersDedupWindow is the number of recent getheaders and
+	// getblocks requests a peer remembers when no window has been set
+	// explicitly via SetGetHeadersDedupWindow.
+	DefaultGetHeadersDedupWindow = 8
+
+	// DefaultGetHeadersDedupTTL is how long a remembered getheaders or
+	// getblocks request is still considered a duplicate when no TTL has
+	// been set explicitly via SetGetHeadersDedupWindow.
+	DefaultGetHeadersDedupTTL = 30 * time.Second
+)
+
+// dedupKey identifies a getheaders/getblocks request by the set of locator
+// hashes it carries and the hash it stops at. Locator hashes are sorted
+// before the key is built so that two requests naming the same DAG
+// locators in a different order are recognized as the same request.
+type dedupKey string
+
+// dedupKeyFor builds the dedupKey for a locator and stop hash.
+func dedupKeyFor(locator []*chainhash.Hash, hashStop *chainhash.Hash) dedupKey {
+	hexes := make([]string, 0, len(locator))
+	for _, hash := range locator {
+		if hash == nil {
+			continue
+		}
+		hexes = append(hexes, hash.String())
+	}
+	sort.Strings(hexes)
+
+	var stop string
+	if hashStop != nil {
+		stop = hashStop.String()
+	}
+
+	return dedupKey(strings.Join(hexes, ",") + "|" + stop)
+}
+
+// dedupEntry is the value stored in a requestDedup's list.List.
+type dedupEntry struct {
+	key     dedupKey
+	expires time.Time
+}
+
+// requestDedup is a small LRU of recently seen dedupKeys, used to recognize
+// back-to-back duplicate getheaders/getblocks requests so they aren't sent
+// to a peer twice in a row. It's safe for concurrent use.
+type requestDedup struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[dedupKey]*list.Element
+}
+
+// newRequestDedup returns a requestDedup that remembers up to size keys for
+// up to ttl each.
+func newRequestDedup(size int, ttl time.Duration) *requestDedup {
+	if size <= 0 {
+		size = DefaultGetHeadersDedupWindow
+	}
+	if ttl <= 0 {
+		ttl = DefaultGetHeadersDedupTTL
+	}
+
+	return &requestDedup{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[dedupKey]*list.Element),
+	}
+}
+
+// seenRecently reports whether key was recorded within the dedup window and
+// hasn't yet expired. If it wasn't, key is recorded as the most recently
+// seen entry and the oldest entries beyond the configured window are
+// evicted.
+func (d *requestDedup) seenRecently(key dedupKey) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if el, ok := d.entries[key]; ok {
+		entry := el.Value.(*dedupEntry)
+		if now.Before(entry.expires) {
+			d.order.MoveToFront(el)
+			entry.expires = now.Add(d.ttl)
+			return true
+		}
+
+		// The entry expired - drop it and fall through to record the
+		// request as new.
+		d.order.Remove(el)
+		delete(d.entries, key)
+	}
+
+	el := d.order.PushFront(&dedupEntry{key: key, expires: now.Add(d.ttl)})
+	d.entries[key] = el
+
+	for d.order.Len() > d.size {
+		oldest := d.order.Back()
+		if oldest == nil {
+			break
+		}
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(*dedupEntry).key)
+	}
+
+	return false
+}
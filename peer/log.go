@@ -0,0 +1,23 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"io/ioutil"
+	"log"
+)
+
+// peerLog is the package-level logger used to report peer diagnostics, such
+// as suppressed duplicate getheaders/getblocks requests. It discards output
+// by default; callers that want to observe these events can install their
+// own logger with UseLogger.
+var peerLog = log.New(ioutil.Discard, "PEER: ", log.LstdFlags)
+
+// UseLogger sets the logger used by the package. Calling it is optional; if
+// it is never called, log output from this package is discarded.
+func UseLogger(logger *log.Logger) {
+	peerLog = logger
+}
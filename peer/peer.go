@@ -0,0 +1,126 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package peer provides the peer-level outbound message helpers used when
+// syncing the DAG with a remote node.
+package peer
+
+import (
+	"time"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+	"github.com/soteria-dag/soterd/wire"
+)
+
+// Config is the collection of options that can be used to configure a Peer.
+type Config struct {
+	// getHeadersDedupWindow and getHeadersDedupTTL control how many
+	// recent getheaders/getblocks requests a Peer remembers, and for how
+	// long, in order to suppress back-to-back duplicates. They're set
+	// through SetGetHeadersDedupWindow rather than directly, so a zero
+	// value Config still gets sane defaults.
+	getHeadersDedupWindow int
+	getHeadersDedupTTL    time.Duration
+}
+
+// SetGetHeadersDedupWindow configures how many recent getheaders/getblocks
+// requests are remembered (n) and for how long (ttl) when deciding whether
+// an outbound request is a duplicate of one sent moments ago. It's most
+// useful during DAG re-sync, where many tips can share locator prefixes and
+// would otherwise generate redundant network traffic.
+func (cfg *Config) SetGetHeadersDedupWindow(n int, ttl time.Duration) {
+	cfg.getHeadersDedupWindow = n
+	cfg.getHeadersDedupTTL = ttl
+}
+
+// MessageSender is the subset of a connected peer's behavior that Peer needs
+// in order to deliver outbound messages. Any type with a QueueMessage method
+// satisfies it, and a plain func can be used through MessageSenderFunc.
+type MessageSender interface {
+	QueueMessage(msg wire.Message, doneChan chan<- struct{})
+}
+
+// MessageSenderFunc adapts a plain function to the MessageSender interface.
+type MessageSenderFunc func(msg wire.Message, doneChan chan<- struct{})
+
+// QueueMessage calls f(msg, doneChan).
+func (f MessageSenderFunc) QueueMessage(msg wire.Message, doneChan chan<- struct{}) {
+	f(msg, doneChan)
+}
+
+// Peer provides the outbound getheaders/getblocks request helpers used to
+// sync the DAG with a connected remote node, along with the duplicate
+// request suppression described on SetGetHeadersDedupWindow.
+type Peer struct {
+	cfg    Config
+	sender MessageSender
+
+	headersDedup *requestDedup
+	blocksDedup  *requestDedup
+}
+
+// NewPeer returns a new Peer that delivers outbound messages through sender.
+func NewPeer(cfg *Config, sender MessageSender) *Peer {
+	c := *cfg
+	return &Peer{
+		cfg:          c,
+		sender:       sender,
+		headersDedup: newRequestDedup(c.getHeadersDedupWindow, c.getHeadersDedupTTL),
+		blocksDedup:  newRequestDedup(c.getHeadersDedupWindow, c.getHeadersDedupTTL),
+	}
+}
+
+// PushGetHeadersMsg sends a getheaders message for the provided block
+// locator and stop hash. If an identical request (same locator hashes,
+// regardless of order, and the same stop hash) was sent within the peer's
+// configured dedup window, the request is suppressed and nil is returned.
+func (p *Peer) PushGetHeadersMsg(locator []*chainhash.Hash, hashStop *chainhash.Hash) error {
+	msg := wire.NewMsgGetHeaders()
+	if hashStop != nil {
+		msg.HashStop = *hashStop
+	}
+	for _, hash := range locator {
+		if err := msg.AddBlockLocatorHash(hash); err != nil {
+			return err
+		}
+	}
+
+	key := dedupKeyFor(locator, hashStop)
+	if p.headersDedup.seenRecently(key) {
+		peerLog.Printf("suppressing duplicate getheaders request "+
+			"(%d locator hashes, stop %s)", len(locator), hashStop)
+		return nil
+	}
+
+	p.sender.QueueMessage(msg, nil)
+	return nil
+}
+
+// PushGetBlocksMsg sends a getblocks message for the provided block locator
+// and stop hash. If an identical request (same locator hashes, regardless
+// of order, and the same stop hash) was sent within the peer's configured
+// dedup window, the request is suppressed and nil is returned.
+func (p *Peer) PushGetBlocksMsg(locator []*chainhash.Hash, hashStop *chainhash.Hash) error {
+	stop := hashStop
+	if stop == nil {
+		stop = &chainhash.Hash{}
+	}
+	msg := wire.NewMsgGetBlocks(stop)
+	for _, hash := range locator {
+		if err := msg.AddBlockLocatorHash(hash); err != nil {
+			return err
+		}
+	}
+
+	key := dedupKeyFor(locator, hashStop)
+	if p.blocksDedup.seenRecently(key) {
+		peerLog.Printf("suppressing duplicate getblocks request "+
+			"(%d locator hashes, stop %s)", len(locator), hashStop)
+		return nil
+	}
+
+	p.sender.QueueMessage(msg, nil)
+	return nil
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+	"github.com/soteria-dag/soterd/wire"
+)
+
+// capturingSender records every message it's asked to queue.
+type capturingSender struct {
+	msgs []wire.Message
+}
+
+func (s *capturingSender) QueueMessage(msg wire.Message, doneChan chan<- struct{}) {
+	s.msgs = append(s.msgs, msg)
+}
+
+func mustHash(t *testing.T, s string) *chainhash.Hash {
+	t.Helper()
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		t.Fatalf("NewHashFromStr(%q): %v", s, err)
+	}
+	return hash
+}
+
+// TestPushGetHeadersMsgDedup verifies that PushGetHeadersMsg suppresses a
+// repeated request for the same locator and stop hash, while a differing
+// locator or stop hash is allowed through.
+func TestPushGetHeadersMsgDedup(t *testing.T) {
+	hashA := mustHash(t, "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0")
+	hashB := mustHash(t, "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535")
+	stop := mustHash(t, "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0")
+
+	tests := []struct {
+		name       string
+		calls      [][]*chainhash.Hash
+		stops      []*chainhash.Hash
+		wantQueued int
+	}{
+		{
+			name:       "identical requests are deduped",
+			calls:      [][]*chainhash.Hash{{hashA, hashB}, {hashA, hashB}, {hashA, hashB}},
+			stops:      []*chainhash.Hash{stop, stop, stop},
+			wantQueued: 1,
+		},
+		{
+			name:       "same locator hashes in a different order are still a duplicate",
+			calls:      [][]*chainhash.Hash{{hashA, hashB}, {hashB, hashA}},
+			stops:      []*chainhash.Hash{stop, stop},
+			wantQueued: 1,
+		},
+		{
+			name:       "differing locator bypasses the filter",
+			calls:      [][]*chainhash.Hash{{hashA}, {hashB}},
+			stops:      []*chainhash.Hash{stop, stop},
+			wantQueued: 2,
+		},
+		{
+			name:       "differing stop hash bypasses the filter",
+			calls:      [][]*chainhash.Hash{{hashA}, {hashA}},
+			stops:      []*chainhash.Hash{hashA, hashB},
+			wantQueued: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &capturingSender{}
+			cfg := &Config{}
+			cfg.SetGetHeadersDedupWindow(8, 30*time.Second)
+			p := NewPeer(cfg, sender)
+
+			for i, locator := range test.calls {
+				if err := p.PushGetHeadersMsg(locator, test.stops[i]); err != nil {
+					t.Fatalf("PushGetHeadersMsg: %v", err)
+				}
+			}
+
+			if len(sender.msgs) != test.wantQueued {
+				t.Errorf("got %d queued messages, want %d",
+					len(sender.msgs), test.wantQueued)
+			}
+		})
+	}
+}
+
+// TestPushGetBlocksMsgDedup mirrors TestPushGetHeadersMsgDedup for
+// PushGetBlocksMsg, and additionally checks that getheaders and getblocks
+// requests are deduped independently of one another.
+func TestPushGetBlocksMsgDedup(t *testing.T) {
+	hashA := mustHash(t, "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0")
+	stop := mustHash(t, "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535")
+
+	sender := &capturingSender{}
+	cfg := &Config{}
+	cfg.SetGetHeadersDedupWindow(8, 30*time.Second)
+	p := NewPeer(cfg, sender)
+
+	locator := []*chainhash.Hash{hashA}
+	if err := p.PushGetBlocksMsg(locator, stop); err != nil {
+		t.Fatalf("PushGetBlocksMsg: %v", err)
+	}
+	if err := p.PushGetBlocksMsg(locator, stop); err != nil {
+		t.Fatalf("PushGetBlocksMsg: %v", err)
+	}
+	if err := p.PushGetHeadersMsg(locator, stop); err != nil {
+		t.Fatalf("PushGetHeadersMsg: %v", err)
+	}
+
+	if len(sender.msgs) != 2 {
+		t.Fatalf("got %d queued messages, want 2", len(sender.msgs))
+	}
+	if _, ok := sender.msgs[0].(*wire.MsgGetBlocks); !ok {
+		t.Errorf("msgs[0]: got %T, want *wire.MsgGetBlocks", sender.msgs[0])
+	}
+	if _, ok := sender.msgs[1].(*wire.MsgGetHeaders); !ok {
+		t.Errorf("msgs[1]: got %T, want *wire.MsgGetHeaders", sender.msgs[1])
+	}
+}
+
+// TestGetHeadersDedupWindowExpiry verifies that a request is no longer
+// treated as a duplicate once the configured TTL has elapsed.
+func TestGetHeadersDedupWindowExpiry(t *testing.T) {
+	hashA := mustHash(t, "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0")
+	stop := mustHash(t, "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535")
+
+	dedup := newRequestDedup(8, 10*time.Millisecond)
+	key := dedupKeyFor([]*chainhash.Hash{hashA}, stop)
+
+	if dedup.seenRecently(key) {
+		t.Fatalf("first request should not be a duplicate")
+	}
+	if !dedup.seenRecently(key) {
+		t.Fatalf("immediate repeat should be a duplicate")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if dedup.seenRecently(key) {
+		t.Fatalf("request after TTL expiry should not be a duplicate")
+	}
+}
+
+// TestGetHeadersDedupWindowEviction verifies that the LRU evicts the oldest
+// entry once more than the configured window of distinct requests has been
+// seen.
+func TestGetHeadersDedupWindowEviction(t *testing.T) {
+	dedup := newRequestDedup(2, time.Minute)
+
+	keyA := dedupKeyFor([]*chainhash.Hash{mustHash(t, "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535")}, nil)
+	keyB := dedupKeyFor([]*chainhash.Hash{mustHash(t, "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0")}, nil)
+	keyC := dedupKeyFor(nil, nil)
+
+	dedup.seenRecently(keyA)
+	dedup.seenRecently(keyB)
+	dedup.seenRecently(keyC)
+
+	if dedup.seenRecently(keyA) {
+		t.Errorf("keyA should have been evicted once the window was exceeded")
+	}
+}
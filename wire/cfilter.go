@@ -0,0 +1,33 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+// FilterType is used to represent a filter type for basic filters that can
+// be requested with a getcfilters, getcfheaders or getcfcheckpt message.
+type FilterType uint8
+
+const (
+	// GCSFilterRegular is the regular filter type, containing spent and
+	// received outputs for each block.
+	GCSFilterRegular FilterType = iota
+)
+
+// MaxCFilterDataSize is the maximum byte size of a committed filter that
+// MsgCFilter is allowed to carry, guarding against malicious or malformed
+// payloads.
+const MaxCFilterDataSize = 256 * 1024
+
+// MaxCFHeaderPayload is the maximum number of filter hashes that can be
+// carried in a single MsgCFHeaders message.
+const MaxCFHeaderPayload = 2000
+
+// MaxCFCheckptInterval is the number of blocks between checkpointed filter
+// headers carried in a MsgCFCheckpt message.
+const MaxCFCheckptInterval = 1000
+
+// MaxCFCheckptsPerMsg is the maximum number of checkpointed filter headers
+// that can be carried in a single MsgCFCheckpt message.
+const MaxCFCheckptsPerMsg = 1000
@@ -0,0 +1,21 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+// Commands used in soterd message headers which describe the type of
+// message.
+const (
+	CmdGetHeaders   = "getheaders"
+	CmdGetBlocks    = "getblocks"
+	CmdGetTips      = "gettips"
+	CmdTips         = "tips"
+	CmdGetCFilters  = "getcfilters"
+	CmdCFilter      = "cfilter"
+	CmdGetCFHeaders = "getcfheaders"
+	CmdCFHeaders    = "cfheaders"
+	CmdGetCFCheckpt = "getcfcheckpt"
+	CmdCFCheckpt    = "cfcheckpt"
+)
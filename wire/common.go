@@ -0,0 +1,242 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// littleEndian is the byte order used by the reading/writing helpers below.
+var littleEndian = binary.LittleEndian
+
+// errNonCanonicalVarInt is the common format string used for errors that
+// detect non-canonically encoded variable length integers.
+const errNonCanonicalVarInt = "non-canonical varint %x - discriminant %x must encode a value greater than %x"
+
+// MaxVarIntPayload is the maximum payload size for a variable length integer.
+const MaxVarIntPayload = 9
+
+// readElement reads the next sequence of bytes from r using little endian
+// byte order for the given concrete fixed-width element.
+func readElement(r io.Reader, element interface{}) error {
+	switch e := element.(type) {
+	case *FilterType:
+		var buf [1]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		*e = FilterType(buf[0])
+		return nil
+
+	case *int32:
+		rv, err := binarySerializerUint32(r)
+		if err != nil {
+			return err
+		}
+		*e = int32(rv)
+		return nil
+
+	case *uint32:
+		rv, err := binarySerializerUint32(r)
+		if err != nil {
+			return err
+		}
+		*e = rv
+		return nil
+
+	case *chainhash.Hash:
+		_, err := io.ReadFull(r, e[:])
+		return err
+	}
+
+	// Fall back to the slower binary.Read for other types.
+	return binary.Read(r, littleEndian, element)
+}
+
+// writeElement writes the little endian byte-order representation of element
+// to w.
+func writeElement(w io.Writer, element interface{}) error {
+	switch e := element.(type) {
+	case FilterType:
+		_, err := w.Write([]byte{uint8(e)})
+		return err
+
+	case int32:
+		return binarySerializerPutUint32(w, uint32(e))
+
+	case uint32:
+		return binarySerializerPutUint32(w, e)
+
+	case chainhash.Hash:
+		_, err := w.Write(e[:])
+		return err
+
+	case *chainhash.Hash:
+		_, err := w.Write(e[:])
+		return err
+	}
+
+	return binary.Write(w, littleEndian, element)
+}
+
+// binarySerializerUint32 reads a little endian encoded uint32 from the
+// reader.  A reader that runs out before all 4 bytes are read yields the
+// error from io.ReadFull.
+func binarySerializerUint32(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return littleEndian.Uint32(buf[:]), nil
+}
+
+// binarySerializerPutUint32 writes a little endian encoded uint32 to the
+// writer.
+func binarySerializerPutUint32(w io.Writer, v uint32) error {
+	var buf [4]byte
+	littleEndian.PutUint32(buf[:], v)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// ReadVarInt reads a variable length integer from r and returns it as a
+// uint64.
+func ReadVarInt(r io.Reader, pver uint32) (uint64, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	discriminant := b[0]
+
+	var rv uint64
+	switch discriminant {
+	case 0xff:
+		var buf [8]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		rv = littleEndian.Uint64(buf[:])
+
+		min := uint64(0x100000000)
+		if rv < min {
+			return 0, messageError("ReadVarInt", fmt.Sprintf(
+				errNonCanonicalVarInt, rv, discriminant, min))
+		}
+
+	case 0xfe:
+		var buf [4]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		rv = uint64(littleEndian.Uint32(buf[:]))
+
+		min := uint64(0x10000)
+		if rv < min {
+			return 0, messageError("ReadVarInt", fmt.Sprintf(
+				errNonCanonicalVarInt, rv, discriminant, min))
+		}
+
+	case 0xfd:
+		var buf [2]byte
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		rv = uint64(littleEndian.Uint16(buf[:]))
+
+		min := uint64(0xfd)
+		if rv < min {
+			return 0, messageError("ReadVarInt", fmt.Sprintf(
+				errNonCanonicalVarInt, rv, discriminant, min))
+		}
+
+	default:
+		rv = uint64(discriminant)
+	}
+
+	return rv, nil
+}
+
+// WriteVarInt serializes val to w using a variable number of bytes depending
+// on its value.
+func WriteVarInt(w io.Writer, pver uint32, val uint64) error {
+	if val < 0xfd {
+		_, err := w.Write([]byte{uint8(val)})
+		return err
+	}
+
+	if val <= 0xffff {
+		buf := make([]byte, 3)
+		buf[0] = 0xfd
+		littleEndian.PutUint16(buf[1:], uint16(val))
+		_, err := w.Write(buf)
+		return err
+	}
+
+	if val <= 0xffffffff {
+		buf := make([]byte, 5)
+		buf[0] = 0xfe
+		littleEndian.PutUint32(buf[1:], uint32(val))
+		_, err := w.Write(buf)
+		return err
+	}
+
+	buf := make([]byte, 9)
+	buf[0] = 0xff
+	littleEndian.PutUint64(buf[1:], val)
+	_, err := w.Write(buf)
+	return err
+}
+
+// VarIntSerializeSize returns the number of bytes it would take to serialize
+// val as a variable length integer.
+func VarIntSerializeSize(val uint64) int {
+	if val < 0xfd {
+		return 1
+	}
+	if val <= 0xffff {
+		return 3
+	}
+	if val <= 0xffffffff {
+		return 5
+	}
+	return 9
+}
+
+// ReadVarBytes reads a variable length byte array, capped at maxAllowed
+// bytes to guard against malicious or malformed payloads, and returns the
+// result as a byte slice.
+func ReadVarBytes(r io.Reader, pver uint32, maxAllowed uint32, fieldName string) ([]byte, error) {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(maxAllowed) {
+		str := fmt.Sprintf("%s is larger than the max allowed size "+
+			"[count %d, max %d]", fieldName, count, maxAllowed)
+		return nil, messageError("ReadVarBytes", str)
+	}
+
+	b := make([]byte, count)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// WriteVarBytes serializes a variable length byte array to w.
+func WriteVarBytes(w io.Writer, pver uint32, bytes []byte) error {
+	slen := uint64(len(bytes))
+	if err := WriteVarInt(w, pver, slen); err != nil {
+		return err
+	}
+
+	_, err := w.Write(bytes)
+	return err
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import "io"
+
+// fixedWriter implements the io.Writer interface and intentionally allows
+// only a fixed number of bytes to be written to it, returning io.ErrShortWrite
+// once that limit is reached.  It's used to force write errors in tests.
+type fixedWriter struct {
+	b   []byte
+	pos int
+}
+
+// Write writes the contents of p to the fixed size buffer, returning
+// io.ErrShortWrite once the buffer's capacity has been exhausted.
+func (w *fixedWriter) Write(p []byte) (n int, err error) {
+	lenp := len(p)
+	if w.pos+lenp > len(w.b) {
+		n = copy(w.b[w.pos:], p)
+		w.pos += n
+		return n, io.ErrShortWrite
+	}
+
+	n = copy(w.b[w.pos:], p)
+	w.pos += n
+	return n, nil
+}
+
+// newFixedWriter returns a new fixedWriter with the given fixed size.
+func newFixedWriter(max int) *fixedWriter {
+	b := make([]byte, max)
+	fw := fixedWriter{b, 0}
+	return &fw
+}
+
+// fixedReader implements the io.Reader interface and intentionally allows
+// only a fixed number of bytes to be read from it, returning io.EOF once
+// that limit is reached.  It's used to force read errors in tests.
+type fixedReader struct {
+	buf []byte
+	pos int
+}
+
+// Read reads up to len(p) bytes into p, returning io.EOF once the fixed
+// size buffer has been exhausted.
+func (r *fixedReader) Read(p []byte) (n int, err error) {
+	n = copy(p, r.buf[r.pos:])
+	r.pos += n
+	if n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// newFixedReader returns a new fixedReader bounded to max bytes of buf.
+func newFixedReader(max int, buf []byte) *fixedReader {
+	b := make([]byte, max)
+	if buf != nil {
+		copy(b, buf)
+	}
+	fr := fixedReader{b, 0}
+	return &fr
+}
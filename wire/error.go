@@ -0,0 +1,34 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import "fmt"
+
+// MessageError describes an issue with a message.
+// An example of some potential issues are messages from the wrong soterd
+// network, invalid commands, mismatched checksums, and exceeding max
+// payloads.
+//
+// This error is used to signal the caller that the error is specifically
+// related to malformed message data as opposed to an underlying system
+// error.
+type MessageError struct {
+	Func        string // Function name
+	Description string // Human readable description of the issue
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *MessageError) Error() string {
+	if e.Func != "" {
+		return fmt.Sprintf("%s: %s", e.Func, e.Description)
+	}
+	return e.Description
+}
+
+// messageError creates an error for the given function and description.
+func messageError(f string, desc string) *MessageError {
+	return &MessageError{Func: f, Description: desc}
+}
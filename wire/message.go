@@ -0,0 +1,73 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// CommandSize is the fixed size of all commands in the common soterd message
+// header.  Shorter commands must be zero padded.
+const CommandSize = 12
+
+// Message is an interface that describes a soterd message.  A type that
+// implements Message has complete control over the representation of its
+// data and may therefore contain additional or different fields than those
+// which are used directly in the protocol encoded on the wire.
+//
+// SotoDecode and SotoEncode read and write the message using the
+// soterd-specific encoding for the protocol version negotiated between
+// peers.
+type Message interface {
+	SotoDecode(io.Reader, uint32, MessageEncoding) error
+	SotoEncode(io.Writer, uint32, MessageEncoding) error
+	Command() string
+	MaxPayloadLength(uint32) uint32
+}
+
+// makeEmptyMessage creates a message of the appropriate concrete type based
+// on the command.
+func makeEmptyMessage(command string) (Message, error) {
+	var msg Message
+
+	switch command {
+	case CmdGetHeaders:
+		msg = &MsgGetHeaders{}
+
+	case CmdGetBlocks:
+		msg = &MsgGetBlocks{}
+
+	case CmdGetTips:
+		msg = &MsgGetTips{}
+
+	case CmdTips:
+		msg = &MsgTips{}
+
+	case CmdGetCFilters:
+		msg = &MsgGetCFilters{}
+
+	case CmdCFilter:
+		msg = &MsgCFilter{}
+
+	case CmdGetCFHeaders:
+		msg = &MsgGetCFHeaders{}
+
+	case CmdCFHeaders:
+		msg = &MsgCFHeaders{}
+
+	case CmdGetCFCheckpt:
+		msg = &MsgGetCFCheckpt{}
+
+	case CmdCFCheckpt:
+		msg = &MsgCFCheckpt{}
+
+	default:
+		return nil, fmt.Errorf("unhandled command [%s]", command)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgCFCheckpt implements the Message interface and represents a soterd
+// cfcheckpt message.  It is sent in response to a getcfcheckpt message and
+// carries committed filter headers spaced MaxCFCheckptInterval blocks
+// apart, up to and including the requested stop hash.
+type MsgCFCheckpt struct {
+	FilterType    FilterType
+	StopHash      chainhash.Hash
+	FilterHeaders []*chainhash.Hash
+}
+
+// AddCFHeader adds a new filter header to the message.
+func (msg *MsgCFCheckpt) AddCFHeader(header *chainhash.Hash) error {
+	if len(msg.FilterHeaders)+1 > MaxCFCheckptsPerMsg {
+		str := fmt.Sprintf("too many filter headers for message [max %v]",
+			MaxCFCheckptsPerMsg)
+		return messageError("MsgCFCheckpt.AddCFHeader", str)
+	}
+
+	msg.FilterHeaders = append(msg.FilterHeaders, header)
+	return nil
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCFCheckpt) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if err := readElement(r, &msg.FilterType); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.StopHash); err != nil {
+		return err
+	}
+
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxCFCheckptsPerMsg {
+		str := fmt.Sprintf("too many filter headers for message "+
+			"[count %v, max %v]", count, MaxCFCheckptsPerMsg)
+		return messageError("MsgCFCheckpt.SotoDecode", str)
+	}
+
+	msg.FilterHeaders = make([]*chainhash.Hash, 0, count)
+	for i := uint64(0); i < count; i++ {
+		header := new(chainhash.Hash)
+		if err := readElement(r, header); err != nil {
+			return err
+		}
+		msg.FilterHeaders = append(msg.FilterHeaders, header)
+	}
+
+	return nil
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCFCheckpt) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	count := len(msg.FilterHeaders)
+	if count > MaxCFCheckptsPerMsg {
+		str := fmt.Sprintf("too many filter headers for message "+
+			"[count %v, max %v]", count, MaxCFCheckptsPerMsg)
+		return messageError("MsgCFCheckpt.SotoEncode", str)
+	}
+
+	if err := writeElement(w, msg.FilterType); err != nil {
+		return err
+	}
+	if err := writeElement(w, &msg.StopHash); err != nil {
+		return err
+	}
+
+	if err := WriteVarInt(w, pver, uint64(count)); err != nil {
+		return err
+	}
+	for _, header := range msg.FilterHeaders {
+		if err := writeElement(w, header); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgCFCheckpt) Command() string {
+	return CmdCFCheckpt
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCFCheckpt) MaxPayloadLength(pver uint32) uint32 {
+	// Filter type 1 byte + stop hash + num filter headers (varInt) + max
+	// allowed filter headers.
+	return 1 + uint32(chainhash.HashSize) + MaxVarIntPayload +
+		MaxCFCheckptsPerMsg*uint32(chainhash.HashSize)
+}
+
+// NewMsgCFCheckpt returns a new soterd cfcheckpt message that conforms to
+// the Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgCFCheckpt(filterType FilterType, stopHash *chainhash.Hash, sizeHint int) *MsgCFCheckpt {
+	return &MsgCFCheckpt{
+		FilterType:    filterType,
+		StopHash:      *stopHash,
+		FilterHeaders: make([]*chainhash.Hash, 0, sizeHint),
+	}
+}
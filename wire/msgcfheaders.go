@@ -0,0 +1,131 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgCFHeaders implements the Message interface and represents a soterd
+// cfheaders message.  It is sent in response to a getcfheaders message and
+// carries a batch of committed filter header hashes, chained from the
+// previous filter header so a light client can verify the whole batch links
+// back to a checkpoint it already trusts.
+type MsgCFHeaders struct {
+	FilterType       FilterType
+	StopHash         chainhash.Hash
+	PrevFilterHeader chainhash.Hash
+	FilterHashes     []*chainhash.Hash
+}
+
+// AddCFHash adds a new filter hash to the message.
+func (msg *MsgCFHeaders) AddCFHash(hash *chainhash.Hash) error {
+	if len(msg.FilterHashes)+1 > MaxCFHeaderPayload {
+		str := fmt.Sprintf("too many block filter hashes for message [max %v]",
+			MaxCFHeaderPayload)
+		return messageError("MsgCFHeaders.AddCFHash", str)
+	}
+
+	msg.FilterHashes = append(msg.FilterHashes, hash)
+	return nil
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCFHeaders) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if err := readElement(r, &msg.FilterType); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.StopHash); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.PrevFilterHeader); err != nil {
+		return err
+	}
+
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxCFHeaderPayload {
+		str := fmt.Sprintf("too many block filter hashes for message "+
+			"[count %v, max %v]", count, MaxCFHeaderPayload)
+		return messageError("MsgCFHeaders.SotoDecode", str)
+	}
+
+	msg.FilterHashes = make([]*chainhash.Hash, 0, count)
+	for i := uint64(0); i < count; i++ {
+		hash := new(chainhash.Hash)
+		if err := readElement(r, hash); err != nil {
+			return err
+		}
+		msg.FilterHashes = append(msg.FilterHashes, hash)
+	}
+
+	return nil
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCFHeaders) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	count := len(msg.FilterHashes)
+	if count > MaxCFHeaderPayload {
+		str := fmt.Sprintf("too many block filter hashes for message "+
+			"[count %v, max %v]", count, MaxCFHeaderPayload)
+		return messageError("MsgCFHeaders.SotoEncode", str)
+	}
+
+	if err := writeElement(w, msg.FilterType); err != nil {
+		return err
+	}
+	if err := writeElement(w, &msg.StopHash); err != nil {
+		return err
+	}
+	if err := writeElement(w, &msg.PrevFilterHeader); err != nil {
+		return err
+	}
+
+	if err := WriteVarInt(w, pver, uint64(count)); err != nil {
+		return err
+	}
+	for _, hash := range msg.FilterHashes {
+		if err := writeElement(w, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgCFHeaders) Command() string {
+	return CmdCFHeaders
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCFHeaders) MaxPayloadLength(pver uint32) uint32 {
+	// Filter type 1 byte + stop hash + prev filter header + num filter
+	// hashes (varInt) + max allowed filter hashes.
+	return 1 + uint32(chainhash.HashSize*2) + MaxVarIntPayload +
+		MaxCFHeaderPayload*uint32(chainhash.HashSize)
+}
+
+// NewMsgCFHeaders returns a new soterd cfheaders message that conforms to
+// the Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgCFHeaders(filterType FilterType, stopHash *chainhash.Hash, prevFilterHeader *chainhash.Hash) *MsgCFHeaders {
+	return &MsgCFHeaders{
+		FilterType:       filterType,
+		StopHash:         *stopHash,
+		PrevFilterHeader: *prevFilterHeader,
+		FilterHashes:     make([]*chainhash.Hash, 0, MaxCFHeaderPayload),
+	}
+}
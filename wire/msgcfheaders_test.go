@@ -0,0 +1,219 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// TestCFHeaders tests the MsgCFHeaders API.
+func TestCFHeaders(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0"
+	stopHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	prevHeader, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	wantCmd := "cfheaders"
+	msg := NewMsgCFHeaders(GCSFilterRegular, stopHash, prevHeader)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgCFHeaders: wrong command - got %v want %v",
+			cmd, wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(1) + uint32(chainhash.HashSize*2) + MaxVarIntPayload +
+		MaxCFHeaderPayload*uint32(chainhash.HashSize)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver,
+			maxPayload, wantPayload)
+	}
+
+	// Ensure filter hashes are added properly.
+	err = msg.AddCFHash(stopHash)
+	if err != nil {
+		t.Errorf("AddCFHash: %v", err)
+	}
+	if !msg.FilterHashes[0].IsEqual(stopHash) {
+		t.Errorf("AddCFHash: wrong hash added - got %v, want %v",
+			spew.Sprint(msg.FilterHashes[0]), stopHash)
+	}
+
+	// Ensure adding more than the max allowed filter hashes per message
+	// returns an error.
+	for i := 0; i < MaxCFHeaderPayload; i++ {
+		err = msg.AddCFHash(stopHash)
+	}
+	if err == nil {
+		t.Errorf("AddCFHash: expected error on too many filter hashes added")
+	}
+}
+
+// TestCFHeadersWire tests the MsgCFHeaders wire encode and decode.
+func TestCFHeadersWire(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	stopHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	prevHeader, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	msg := NewMsgCFHeaders(GCSFilterRegular, stopHash, prevHeader)
+	if err := msg.AddCFHash(stopHash); err != nil {
+		t.Errorf("AddCFHash: %v", err)
+	}
+	msgEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Stop hash
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Prev filter header
+		0x01, // Varint for number of filter hashes
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Filter hash
+	}
+
+	var buf bytes.Buffer
+	if err := msg.SotoEncode(&buf, pver, BaseEncoding); err != nil {
+		t.Errorf("SotoEncode error %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), msgEncoded) {
+		t.Errorf("SotoEncode\n got: %s want: %s", spew.Sdump(buf.Bytes()),
+			spew.Sdump(msgEncoded))
+	}
+
+	var decoded MsgCFHeaders
+	if err := decoded.SotoDecode(bytes.NewReader(msgEncoded), pver, BaseEncoding); err != nil {
+		t.Errorf("SotoDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Errorf("SotoDecode\n got: %s want: %s", spew.Sdump(&decoded),
+			spew.Sdump(msg))
+	}
+}
+
+// TestCFHeadersWireErrors performs negative tests against wire encode and
+// decode of MsgCFHeaders to confirm error paths work correctly.
+func TestCFHeadersWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+	wireErr := &MessageError{}
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	stopHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	prevHeader, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	baseHeaders := NewMsgCFHeaders(GCSFilterRegular, stopHash, prevHeader)
+	if err := baseHeaders.AddCFHash(stopHash); err != nil {
+		t.Errorf("AddCFHash: %v", err)
+	}
+	baseHeadersEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Stop hash
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Prev filter header
+		0x01, // Varint for number of filter hashes
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Filter hash
+	}
+
+	// Message that forces an error by having more than the max allowed
+	// filter hashes.
+	maxHeaders := NewMsgCFHeaders(GCSFilterRegular, stopHash, prevHeader)
+	for i := 0; i < MaxCFHeaderPayload; i++ {
+		_ = maxHeaders.AddCFHash(stopHash)
+	}
+	maxHeaders.FilterHashes = append(maxHeaders.FilterHashes, stopHash)
+	maxHeadersEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Stop hash
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Prev filter header
+		0xfd, 0xd1, 0x07, // Varint for number of filter hashes (2001)
+	}
+
+	tests := []struct {
+		in       *MsgCFHeaders
+		buf      []byte
+		max      int
+		writeErr error
+		readErr  error
+	}{
+		// Force error in filter type.
+		{baseHeaders, baseHeadersEncoded, 0, io.ErrShortWrite, io.EOF},
+		// Force error in stop hash.
+		{baseHeaders, baseHeadersEncoded, 1, io.ErrShortWrite, io.EOF},
+		// Force error in prev filter header.
+		{baseHeaders, baseHeadersEncoded, 33, io.ErrShortWrite, io.EOF},
+		// Force error in filter hash count.
+		{baseHeaders, baseHeadersEncoded, 65, io.ErrShortWrite, io.EOF},
+		// Force error in filter hash.
+		{baseHeaders, baseHeadersEncoded, 66, io.ErrShortWrite, io.EOF},
+		// Force error with greater than max filter hashes.
+		{maxHeaders, maxHeadersEncoded, 68, wireErr, wireErr},
+	}
+
+	for i, test := range tests {
+		w := newFixedWriter(test.max)
+		err := test.in.SotoEncode(w, ProtocolVersion, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.writeErr) {
+			t.Errorf("SotoEncode #%d wrong error got: %v, want: %v",
+				i, err, test.writeErr)
+			continue
+		}
+
+		var got MsgCFHeaders
+		r := newFixedReader(test.max, test.buf)
+		err = got.SotoDecode(r, pver, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.readErr) {
+			t.Errorf("SotoDecode #%d wrong error got: %v, want: %v",
+				i, err, test.readErr)
+			continue
+		}
+	}
+}
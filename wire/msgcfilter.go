@@ -0,0 +1,85 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgCFilter implements the Message interface and represents a soterd
+// cfilter message.  It is sent in response to a getcfilters message and
+// carries the raw bytes of a single committed filter for the given block.
+type MsgCFilter struct {
+	FilterType FilterType
+	BlockHash  chainhash.Hash
+	Data       []byte
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCFilter) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if err := readElement(r, &msg.FilterType); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.BlockHash); err != nil {
+		return err
+	}
+
+	data, err := ReadVarBytes(r, pver, MaxCFilterDataSize, "cfilter data")
+	if err != nil {
+		return err
+	}
+	msg.Data = data
+
+	return nil
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCFilter) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	if len(msg.Data) > MaxCFilterDataSize {
+		str := fmt.Sprintf("cfilter data is larger than the max allowed "+
+			"size [len %v, max %v]", len(msg.Data), MaxCFilterDataSize)
+		return messageError("MsgCFilter.SotoEncode", str)
+	}
+
+	if err := writeElement(w, msg.FilterType); err != nil {
+		return err
+	}
+	if err := writeElement(w, &msg.BlockHash); err != nil {
+		return err
+	}
+
+	return WriteVarBytes(w, pver, msg.Data)
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgCFilter) Command() string {
+	return CmdCFilter
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCFilter) MaxPayloadLength(pver uint32) uint32 {
+	// Filter type 1 byte + block hash + num filter bytes (varInt) + max
+	// allowed filter bytes.
+	return 1 + uint32(chainhash.HashSize) + MaxVarIntPayload + MaxCFilterDataSize
+}
+
+// NewMsgCFilter returns a new soterd cfilter message that conforms to the
+// Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgCFilter(filterType FilterType, blockHash *chainhash.Hash, data []byte) *MsgCFilter {
+	return &MsgCFilter{
+		FilterType: filterType,
+		BlockHash:  *blockHash,
+		Data:       data,
+	}
+}
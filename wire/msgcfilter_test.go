@@ -0,0 +1,168 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// TestCFilter tests the MsgCFilter API.
+func TestCFilter(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0"
+	blockHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	filterData := []byte{0x01, 0x02, 0x03}
+
+	wantCmd := "cfilter"
+	msg := NewMsgCFilter(GCSFilterRegular, blockHash, filterData)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgCFilter: wrong command - got %v want %v",
+			cmd, wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	wantPayload := uint32(1) + uint32(chainhash.HashSize) + MaxVarIntPayload +
+		MaxCFilterDataSize
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver,
+			maxPayload, wantPayload)
+	}
+
+	if !bytes.Equal(msg.Data, filterData) {
+		t.Errorf("NewMsgCFilter: wrong data - got %v, want %v",
+			msg.Data, filterData)
+	}
+}
+
+// TestCFilterWire tests the MsgCFilter wire encode and decode.
+func TestCFilterWire(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	blockHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	filterData := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	msg := NewMsgCFilter(GCSFilterRegular, blockHash, filterData)
+	msgEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // block hash
+		0x04,                   // Varint for data len
+		0xde, 0xad, 0xbe, 0xef, // Data
+	}
+
+	var buf bytes.Buffer
+	if err := msg.SotoEncode(&buf, pver, BaseEncoding); err != nil {
+		t.Errorf("SotoEncode error %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), msgEncoded) {
+		t.Errorf("SotoEncode\n got: %s want: %s", spew.Sdump(buf.Bytes()),
+			spew.Sdump(msgEncoded))
+	}
+
+	var decoded MsgCFilter
+	if err := decoded.SotoDecode(bytes.NewReader(msgEncoded), pver, BaseEncoding); err != nil {
+		t.Errorf("SotoDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Errorf("SotoDecode\n got: %s want: %s", spew.Sdump(&decoded),
+			spew.Sdump(msg))
+	}
+}
+
+// TestCFilterWireErrors performs negative tests against wire encode and
+// decode of MsgCFilter to confirm error paths work correctly.
+func TestCFilterWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	blockHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	filterData := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	msg := NewMsgCFilter(GCSFilterRegular, blockHash, filterData)
+	msgEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // block hash
+		0x04,                   // Varint for data len
+		0xde, 0xad, 0xbe, 0xef, // Data
+	}
+
+	wireErr := &MessageError{}
+
+	// Message that forces an error by having data larger than the max
+	// allowed size.
+	oversizedData := make([]byte, MaxCFilterDataSize+1)
+	oversizedMsg := NewMsgCFilter(GCSFilterRegular, blockHash, oversizedData)
+	oversizedMsgEncoded := []byte{
+		0x00, // Filter type
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // block hash
+		0xfe, 0x01, 0x00, 0x04, 0x00, // Varint for data len (262145)
+	}
+
+	tests := []struct {
+		in       *MsgCFilter
+		buf      []byte
+		max      int
+		writeErr error
+		readErr  error
+	}{
+		// Force error in filter type.
+		{msg, msgEncoded, 0, io.ErrShortWrite, io.EOF},
+		// Force error in block hash.
+		{msg, msgEncoded, 1, io.ErrShortWrite, io.EOF},
+		// Force error in data len.
+		{msg, msgEncoded, 33, io.ErrShortWrite, io.EOF},
+		// Force error in data.
+		{msg, msgEncoded, 34, io.ErrShortWrite, io.EOF},
+		// Force error with data larger than the max allowed size.
+		{oversizedMsg, oversizedMsgEncoded, 38, wireErr, wireErr},
+	}
+
+	for i, test := range tests {
+		w := newFixedWriter(test.max)
+		err := test.in.SotoEncode(w, ProtocolVersion, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.writeErr) {
+			t.Errorf("SotoEncode #%d wrong error got: %v, want: %v",
+				i, err, test.writeErr)
+			continue
+		}
+
+		var got MsgCFilter
+		r := newFixedReader(test.max, test.buf)
+		err = got.SotoDecode(r, pver, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.readErr) {
+			t.Errorf("SotoDecode #%d wrong error got: %v, want: %v",
+				i, err, test.readErr)
+			continue
+		}
+	}
+}
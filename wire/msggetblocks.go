@@ -0,0 +1,190 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgGetBlocks implements the Message interface and represents a soterd
+// getblocks message.  It is used to request a list of blocks starting after
+// the last known block in the locator until the provided stop hash is
+// reached, or up to a max of 500 blocks.
+//
+// As with MsgGetHeaders, the locator heights are paired (by index) with
+// BlockLocatorHashes when the peer negotiates BlockLocatorHashVersion or
+// later, since a height alone doesn't identify a unique block in the DAG.
+type MsgGetBlocks struct {
+	ProtocolVersion    uint32
+	BlockLocatorHeight []*int32
+	BlockLocatorHashes []*chainhash.Hash
+	HashStop           chainhash.Hash
+}
+
+// AddBlockLocatorHeight adds a new block locator height to the message.
+func (msg *MsgGetBlocks) AddBlockLocatorHeight(height *int32) error {
+	if len(msg.BlockLocatorHeight)+1 > MaxBlockLocatorsPerMsg {
+		str := fmt.Sprintf("too many block locator heights for message [max %v]",
+			MaxBlockLocatorsPerMsg)
+		return messageError("MsgGetBlocks.AddBlockLocatorHeight", str)
+	}
+
+	msg.BlockLocatorHeight = append(msg.BlockLocatorHeight, height)
+	return nil
+}
+
+// AddBlockLocatorHash adds a new block locator hash to the message.  The
+// hash at a given index pairs with the height at the same index in
+// BlockLocatorHeight, so the two should be added together.
+func (msg *MsgGetBlocks) AddBlockLocatorHash(hash *chainhash.Hash) error {
+	if len(msg.BlockLocatorHashes)+1 > MaxBlockLocatorsPerMsg {
+		str := fmt.Sprintf("too many block locator hashes for message [max %v]",
+			MaxBlockLocatorsPerMsg)
+		return messageError("MsgGetBlocks.AddBlockLocatorHash", str)
+	}
+
+	msg.BlockLocatorHashes = append(msg.BlockLocatorHashes, hash)
+	return nil
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlocks) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	err := readElement(r, &msg.ProtocolVersion)
+	if err != nil {
+		return err
+	}
+
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxBlockLocatorsPerMsg {
+		str := fmt.Sprintf("too many block locator heights for message "+
+			"[count %v, max %v]", count, MaxBlockLocatorsPerMsg)
+		return messageError("MsgGetBlocks.SotoDecode", str)
+	}
+
+	msg.BlockLocatorHeight = make([]*int32, 0, count)
+	for i := uint64(0); i < count; i++ {
+		height := new(int32)
+		err := readElement(r, height)
+		if err != nil {
+			return err
+		}
+		msg.BlockLocatorHeight = append(msg.BlockLocatorHeight, height)
+	}
+
+	msg.BlockLocatorHashes = make([]*chainhash.Hash, 0)
+	if pver >= BlockLocatorHashVersion {
+		hcount, err := ReadVarInt(r, pver)
+		if err != nil {
+			return err
+		}
+		if hcount > MaxBlockLocatorsPerMsg {
+			str := fmt.Sprintf("too many block locator hashes for message "+
+				"[count %v, max %v]", hcount, MaxBlockLocatorsPerMsg)
+			return messageError("MsgGetBlocks.SotoDecode", str)
+		}
+
+		msg.BlockLocatorHashes = make([]*chainhash.Hash, 0, hcount)
+		for i := uint64(0); i < hcount; i++ {
+			hash := new(chainhash.Hash)
+			err := readElement(r, hash)
+			if err != nil {
+				return err
+			}
+			msg.BlockLocatorHashes = append(msg.BlockLocatorHashes, hash)
+		}
+	}
+
+	return readElement(r, &msg.HashStop)
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlocks) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	count := len(msg.BlockLocatorHeight)
+	if count > MaxBlockLocatorsPerMsg {
+		str := fmt.Sprintf("too many block locator heights for message "+
+			"[count %v, max %v]", count, MaxBlockLocatorsPerMsg)
+		return messageError("MsgGetBlocks.SotoEncode", str)
+	}
+
+	err := writeElement(w, msg.ProtocolVersion)
+	if err != nil {
+		return err
+	}
+
+	err = WriteVarInt(w, pver, uint64(count))
+	if err != nil {
+		return err
+	}
+
+	for _, height := range msg.BlockLocatorHeight {
+		err = writeElement(w, *height)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pver >= BlockLocatorHashVersion {
+		hcount := len(msg.BlockLocatorHashes)
+		if hcount > MaxBlockLocatorsPerMsg {
+			str := fmt.Sprintf("too many block locator hashes for message "+
+				"[count %v, max %v]", hcount, MaxBlockLocatorsPerMsg)
+			return messageError("MsgGetBlocks.SotoEncode", str)
+		}
+
+		err = WriteVarInt(w, pver, uint64(hcount))
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range msg.BlockLocatorHashes {
+			err = writeElement(w, hash)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeElement(w, &msg.HashStop)
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgGetBlocks) Command() string {
+	return CmdGetBlocks
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlocks) MaxPayloadLength(pver uint32) uint32 {
+	// Protocol version 4 bytes + num locator heights (varInt) + max
+	// allowed locator heights + hash stop.
+	length := 4 + MaxVarIntPayload + (MaxBlockLocatorsPerMsg * 4) + chainhash.HashSize
+
+	if pver >= BlockLocatorHashVersion {
+		length += MaxVarIntPayload + (MaxBlockLocatorsPerMsg * chainhash.HashSize)
+	}
+
+	return uint32(length)
+}
+
+// NewMsgGetBlocks returns a new soterd getblocks message that conforms to
+// the Message interface.  See MsgGetBlocks for details.
+func NewMsgGetBlocks(hashStop *chainhash.Hash) *MsgGetBlocks {
+	return &MsgGetBlocks{
+		ProtocolVersion:    ProtocolVersion,
+		BlockLocatorHeight: make([]*int32, 0, MaxBlockLocatorsPerMsg),
+		BlockLocatorHashes: make([]*chainhash.Hash, 0, MaxBlockLocatorsPerMsg),
+		HashStop:           *hashStop,
+	}
+}
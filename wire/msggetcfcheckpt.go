@@ -0,0 +1,63 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgGetCFCheckpt implements the Message interface and represents a soterd
+// getcfcheckpt message.  It is used to request committed filter header
+// checkpoints spaced MaxCFCheckptInterval blocks apart, up to and including
+// the given stop hash, which a light client can use to jump-verify a
+// cfheaders batch without walking every checkpoint interval individually.
+type MsgGetCFCheckpt struct {
+	FilterType FilterType
+	StopHash   chainhash.Hash
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetCFCheckpt) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if err := readElement(r, &msg.FilterType); err != nil {
+		return err
+	}
+	return readElement(r, &msg.StopHash)
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetCFCheckpt) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	if err := writeElement(w, msg.FilterType); err != nil {
+		return err
+	}
+	return writeElement(w, &msg.StopHash)
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgGetCFCheckpt) Command() string {
+	return CmdGetCFCheckpt
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetCFCheckpt) MaxPayloadLength(pver uint32) uint32 {
+	// Filter type 1 byte + stop hash.
+	return 1 + chainhash.HashSize
+}
+
+// NewMsgGetCFCheckpt returns a new soterd getcfcheckpt message that conforms
+// to the Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgGetCFCheckpt(filterType FilterType, stopHash *chainhash.Hash) *MsgGetCFCheckpt {
+	return &MsgGetCFCheckpt{
+		FilterType: filterType,
+		StopHash:   *stopHash,
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MsgGetCFilters implements the Message interface and represents a soterd
+// getcfilters message.  It is used to request committed filters for a range
+// of blocks, identified by start height and an inclusive stop hash, so a
+// light client can decide which blocks are worth downloading without
+// fetching them first.
+type MsgGetCFilters struct {
+	FilterType  FilterType
+	StartHeight uint32
+	StopHash    chainhash.Hash
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetCFilters) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if err := readElement(r, &msg.FilterType); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.StartHeight); err != nil {
+		return err
+	}
+	return readElement(r, &msg.StopHash)
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetCFilters) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	if err := writeElement(w, msg.FilterType); err != nil {
+		return err
+	}
+	if err := writeElement(w, msg.StartHeight); err != nil {
+		return err
+	}
+	return writeElement(w, &msg.StopHash)
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgGetCFilters) Command() string {
+	return CmdGetCFilters
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetCFilters) MaxPayloadLength(pver uint32) uint32 {
+	// Filter type 1 byte + start height 4 bytes + stop hash.
+	return 1 + 4 + chainhash.HashSize
+}
+
+// NewMsgGetCFilters returns a new soterd getcfilters message that conforms
+// to the Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgGetCFilters(filterType FilterType, startHeight uint32, stopHash *chainhash.Hash) *MsgGetCFilters {
+	return &MsgGetCFilters{
+		FilterType:  filterType,
+		StartHeight: startHeight,
+		StopHash:    *stopHash,
+	}
+}
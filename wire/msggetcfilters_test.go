@@ -0,0 +1,152 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// TestGetCFilters tests the MsgGetCFilters API.
+func TestGetCFilters(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0"
+	hashStop, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	wantCmd := "getcfilters"
+	msg := NewMsgGetCFilters(GCSFilterRegular, 99500, hashStop)
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgGetCFilters: wrong command - got %v want %v",
+			cmd, wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Filter type 1 byte + start height 4 bytes + stop hash.
+	wantPayload := uint32(1 + 4 + chainhash.HashSize)
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver,
+			maxPayload, wantPayload)
+	}
+
+	if msg.FilterType != GCSFilterRegular {
+		t.Errorf("NewMsgGetCFilters: wrong filter type - got %v, want %v",
+			msg.FilterType, GCSFilterRegular)
+	}
+	if msg.StartHeight != 99500 {
+		t.Errorf("NewMsgGetCFilters: wrong start height - got %v, want %v",
+			msg.StartHeight, 99500)
+	}
+	if !msg.StopHash.IsEqual(hashStop) {
+		t.Errorf("NewMsgGetCFilters: wrong stop hash - got %v, want %v",
+			spew.Sprint(msg.StopHash), spew.Sprint(hashStop))
+	}
+}
+
+// TestGetCFiltersWire tests the MsgGetCFilters wire encode and decode.
+func TestGetCFiltersWire(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	hashStop, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	msg := NewMsgGetCFilters(GCSFilterRegular, 99499, hashStop)
+	msgEncoded := []byte{
+		0x00,                   // Filter type
+		0xab, 0x84, 0x01, 0x00, // Start height
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Stop hash
+	}
+
+	var buf bytes.Buffer
+	if err := msg.SotoEncode(&buf, pver, BaseEncoding); err != nil {
+		t.Errorf("SotoEncode error %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), msgEncoded) {
+		t.Errorf("SotoEncode\n got: %s want: %s", spew.Sdump(buf.Bytes()),
+			spew.Sdump(msgEncoded))
+	}
+
+	var decoded MsgGetCFilters
+	if err := decoded.SotoDecode(bytes.NewReader(msgEncoded), pver, BaseEncoding); err != nil {
+		t.Errorf("SotoDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Errorf("SotoDecode\n got: %s want: %s", spew.Sdump(&decoded),
+			spew.Sdump(msg))
+	}
+}
+
+// TestGetCFiltersWireErrors performs negative tests against wire encode and
+// decode of MsgGetCFilters to confirm error paths work correctly.
+func TestGetCFiltersWireErrors(t *testing.T) {
+	pver := ProtocolVersion
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	hashStop, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	msg := NewMsgGetCFilters(GCSFilterRegular, 99499, hashStop)
+	msgEncoded := []byte{
+		0x00,                   // Filter type
+		0xab, 0x84, 0x01, 0x00, // Start height
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // Stop hash
+	}
+
+	tests := []struct {
+		in       *MsgGetCFilters
+		buf      []byte
+		max      int
+		writeErr error
+		readErr  error
+	}{
+		// Force error in filter type.
+		{msg, msgEncoded, 0, io.ErrShortWrite, io.EOF},
+		// Force error in start height.
+		{msg, msgEncoded, 1, io.ErrShortWrite, io.EOF},
+		// Force error in stop hash.
+		{msg, msgEncoded, 5, io.ErrShortWrite, io.EOF},
+	}
+
+	for i, test := range tests {
+		w := newFixedWriter(test.max)
+		err := test.in.SotoEncode(w, ProtocolVersion, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.writeErr) {
+			t.Errorf("SotoEncode #%d wrong error got: %v, want: %v",
+				i, err, test.writeErr)
+			continue
+		}
+
+		var got MsgGetCFilters
+		r := newFixedReader(test.max, test.buf)
+		err = got.SotoDecode(r, pver, BaseEncoding)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.readErr) {
+			t.Errorf("SotoDecode #%d wrong error got: %v, want: %v",
+				i, err, test.readErr)
+			continue
+		}
+	}
+}
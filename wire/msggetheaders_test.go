@@ -11,8 +11,8 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/soteria-dag/soterd/chaincfg/chainhash"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
 )
 
 // TestGetHeaders tests the MsgGetHeader API.
@@ -37,9 +37,11 @@ func TestGetHeaders(t *testing.T) {
 	}
 
 	// Ensure max payload is expected value for latest protocol version.
-	// Protocol version 4 bytes + num hashes (varInt) + max block locator
-	// hashes + hash stop.
-	wantPayload := uint32(4 + 9 + (4 * 1) + 32)
+	// Protocol version 4 bytes + num heights (varInt) + max block locator
+	// heights + num hashes (varInt) + max block locator hashes + hash
+	// stop.
+	wantPayload := uint32(4 + 9 + (4 * MaxBlockLocatorsPerMsg) + 9 +
+		(chainhash.HashSize * MaxBlockLocatorsPerMsg) + chainhash.HashSize)
 	maxPayload := msg.MaxPayloadLength(pver)
 	if maxPayload != wantPayload {
 		t.Errorf("MaxPayloadLength: wrong max payload length for "+
@@ -47,7 +49,7 @@ func TestGetHeaders(t *testing.T) {
 			maxPayload, wantPayload)
 	}
 
-	// Ensure block locator hashes are added properly.
+	// Ensure block locator heights are added properly.
 	err = msg.AddBlockLocatorHeight(locatorHeight)
 	if err != nil {
 		t.Errorf("AddBlockLocatorHeight: %v", err)
@@ -59,7 +61,23 @@ func TestGetHeaders(t *testing.T) {
 			spew.Sprint(locatorHeight))
 	}
 
-	// Ensure adding more than the max allowed block locator hashes per
+	// Ensure block locator hashes are added properly.
+	locatorHash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	err = msg.AddBlockLocatorHash(locatorHash)
+	if err != nil {
+		t.Errorf("AddBlockLocatorHash: %v", err)
+	}
+	if !msg.BlockLocatorHashes[0].IsEqual(locatorHash) {
+		t.Errorf("AddBlockLocatorHash: wrong block locator added - "+
+			"got %v, want %v",
+			spew.Sprint(msg.BlockLocatorHashes[0]),
+			spew.Sprint(locatorHash))
+	}
+
+	// Ensure adding more than the max allowed block locator heights per
 	// message returns an error.
 	for i := 0; i < MaxBlockLocatorsPerMsg; i++ {
 		err = msg.AddBlockLocatorHeight(locatorHeight)
@@ -68,6 +86,16 @@ func TestGetHeaders(t *testing.T) {
 		t.Errorf("AddBlockLocatorHeight: expected error on too many " +
 			"block locator heights added")
 	}
+
+	// Ensure adding more than the max allowed block locator hashes per
+	// message returns an error.
+	for i := 0; i < MaxBlockLocatorsPerMsg; i++ {
+		err = msg.AddBlockLocatorHash(locatorHash)
+	}
+	if err == nil {
+		t.Errorf("AddBlockLocatorHash: expected error on too many " +
+			"block locator hashes added")
+	}
 }
 
 // TestGetHeadersWire tests the MsgGetHeaders wire encode and decode for various
@@ -93,7 +121,7 @@ func TestGetHeadersWire(t *testing.T) {
 	noLocators.ProtocolVersion = pver
 	noLocatorsEncoded := []byte{
 		0x62, 0xea, 0x00, 0x00, // Protocol version 60002
-		0x00, // Varint for number of block locator hashes
+		0x00,                   // Varint for number of block locator heights
 		0x00, 0x00, 0x00, 0x00, // locator height
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -108,8 +136,31 @@ func TestGetHeadersWire(t *testing.T) {
 	multiLocators.AddBlockLocatorHeight(locatorHeight)
 	multiLocatorsEncoded := []byte{
 		0x62, 0xea, 0x00, 0x00, // Protocol version 60002
-		0x01, // Varint for number of block locator hashes
+		0x01,                   // Varint for number of block locator heights
+		0xab, 0x84, 0x01, 0x00, // locatorHeight
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // hashStop
+	}
+
+	// ProtocolVersion is at least BlockLocatorHashVersion, so the encoding
+	// at the latest protocol version also carries the (empty) block
+	// locator hash count.
+	noLocatorsEncodedLatest := []byte{
+		0x62, 0xea, 0x00, 0x00, // Protocol version 60002
+		0x00, // Varint for number of block locator heights
+		0x00, // Varint for number of block locator hashes
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // hashStop
+	}
+	multiLocatorsEncodedLatest := []byte{
+		0x62, 0xea, 0x00, 0x00, // Protocol version 60002
+		0x01,                   // Varint for number of block locator heights
 		0xab, 0x84, 0x01, 0x00, // locatorHeight
+		0x00, // Varint for number of block locator hashes
 		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
 		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
 		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
@@ -127,7 +178,7 @@ func TestGetHeadersWire(t *testing.T) {
 		{
 			noLocators,
 			noLocators,
-			noLocatorsEncoded,
+			noLocatorsEncodedLatest,
 			ProtocolVersion,
 			BaseEncoding,
 		},
@@ -136,7 +187,7 @@ func TestGetHeadersWire(t *testing.T) {
 		{
 			multiLocators,
 			multiLocators,
-			multiLocatorsEncoded,
+			multiLocatorsEncodedLatest,
 			ProtocolVersion,
 			BaseEncoding,
 		},
@@ -274,7 +325,7 @@ func TestGetHeadersWireErrors(t *testing.T) {
 	}
 	baseGetHeadersEncoded := []byte{
 		0x62, 0xea, 0x00, 0x00, // Protocol version 60002
-		0x01, // Varint for number of block locator hashes
+		0x01,                   // Varint for number of block locator heights
 		0xab, 0x84, 0x01, 0x00, // locatorHeight
 		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
 		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
@@ -295,6 +346,22 @@ func TestGetHeadersWireErrors(t *testing.T) {
 		0xfd, 0xf5, 0x01, // Varint for number of block loc hashes (501)
 	}
 
+	// Message that forces an error by having more than the max allowed
+	// block locator hashes, exercised by a peer on BlockLocatorHashVersion
+	// or later.
+	maxGetHeadersHashes := NewMsgGetHeaders()
+	maxGetHeadersHashes.ProtocolVersion = BlockLocatorHashVersion
+	for i := 0; i < MaxBlockLocatorsPerMsg; i++ {
+		_ = maxGetHeadersHashes.AddBlockLocatorHash(hashStop)
+	}
+	maxGetHeadersHashes.BlockLocatorHashes = append(
+		maxGetHeadersHashes.BlockLocatorHashes, hashStop)
+	maxGetHeadersHashesEncoded := []byte{
+		0x7f, 0x11, 0x01, 0x00, // Protocol version BlockLocatorHashVersion
+		0x00,             // Varint for zero block locator heights
+		0xfd, 0xf5, 0x01, // Varint for number of block loc hashes (501)
+	}
+
 	tests := []struct {
 		in       *MsgGetHeaders  // Value to encode
 		buf      []byte          // Wire encoding
@@ -314,6 +381,9 @@ func TestGetHeadersWireErrors(t *testing.T) {
 		{baseGetHeaders, baseGetHeadersEncoded, pver, BaseEncoding, 9, io.ErrShortWrite, io.EOF},
 		// Force error with greater than max block locator hashes.
 		{maxGetHeaders, maxGetHeadersEncoded, pver, BaseEncoding, 7, wireErr, wireErr},
+		// Force error with greater than max block locator hashes
+		// (BlockLocatorHashes, not BlockLocatorHeight).
+		{maxGetHeadersHashes, maxGetHeadersHashesEncoded, BlockLocatorHashVersion, BaseEncoding, 8, wireErr, wireErr},
 	}
 
 	t.Logf("Running %d tests", len(tests))
@@ -358,3 +428,58 @@ func TestGetHeadersWireErrors(t *testing.T) {
 		}
 	}
 }
+
+// TestGetHeadersWireHashLocators tests that MsgGetHeaders carries
+// BlockLocatorHashes alongside BlockLocatorHeight when the peer negotiates
+// BlockLocatorHashVersion or later, and that older peers never see the hash
+// list on the wire.
+func TestGetHeadersWireHashLocators(t *testing.T) {
+	height := int32(99499)
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+
+	msg := NewMsgGetHeaders()
+	msg.ProtocolVersion = BlockLocatorHashVersion
+	msg.HashStop = *hash
+	if err := msg.AddBlockLocatorHeight(&height); err != nil {
+		t.Errorf("AddBlockLocatorHeight: %v", err)
+	}
+	if err := msg.AddBlockLocatorHash(hash); err != nil {
+		t.Errorf("AddBlockLocatorHash: %v", err)
+	}
+
+	// A peer on BlockLocatorHashVersion should round-trip the hash list.
+	var buf bytes.Buffer
+	if err := msg.SotoEncode(&buf, BlockLocatorHashVersion, BaseEncoding); err != nil {
+		t.Fatalf("SotoEncode: %v", err)
+	}
+
+	var decoded MsgGetHeaders
+	if err := decoded.SotoDecode(bytes.NewReader(buf.Bytes()), BlockLocatorHashVersion, BaseEncoding); err != nil {
+		t.Fatalf("SotoDecode: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, msg) {
+		t.Errorf("SotoDecode\n got: %s want: %s", spew.Sdump(&decoded),
+			spew.Sdump(msg))
+	}
+
+	// A peer that hasn't negotiated BlockLocatorHashVersion should never
+	// see the hash list on the wire.
+	var oldBuf bytes.Buffer
+	if err := msg.SotoEncode(&oldBuf, BIP0035Version, BaseEncoding); err != nil {
+		t.Fatalf("SotoEncode: %v", err)
+	}
+
+	var oldDecoded MsgGetHeaders
+	if err := oldDecoded.SotoDecode(bytes.NewReader(oldBuf.Bytes()), BIP0035Version, BaseEncoding); err != nil {
+		t.Fatalf("SotoDecode: %v", err)
+	}
+	if len(oldDecoded.BlockLocatorHashes) != 0 {
+		t.Errorf("SotoDecode: expected no block locator hashes for "+
+			"protocol version %d, got %d", BIP0035Version,
+			len(oldDecoded.BlockLocatorHashes))
+	}
+}
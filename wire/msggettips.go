@@ -0,0 +1,48 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import "io"
+
+// MsgGetTips implements the Message interface and represents a soterd
+// gettips message.  It is used to request the set of current DAG tips from
+// a peer, which the caller can then feed directly into
+// MsgGetHeaders.BlockLocatorHashes to build a locator describing the whole
+// frontier rather than a single chain tip.
+//
+// MsgGetTips carries no payload; the command itself is the request.
+type MsgGetTips struct {
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetTips) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	return nil
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetTips) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgGetTips) Command() string {
+	return CmdGetTips
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetTips) MaxPayloadLength(pver uint32) uint32 {
+	return 0
+}
+
+// NewMsgGetTips returns a new soterd gettips message that conforms to the
+// Message interface.  See MsgGetTips for details.
+func NewMsgGetTips() *MsgGetTips {
+	return &MsgGetTips{}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// MaxTipsPerMsg is the maximum number of DAG tips allowed in a single
+// MsgTips message.
+const MaxTipsPerMsg = 1000
+
+// Tip describes a single DAG tip: the hash of the tip block, paired with
+// its height so a peer doesn't have to look it up before using it as a
+// block locator entry.
+type Tip struct {
+	Hash   chainhash.Hash
+	Height int32
+}
+
+// MsgTips implements the Message interface and represents a soterd tips
+// message.  It is sent in response to a gettips message and carries the
+// full set of current DAG tips known to the peer.
+type MsgTips struct {
+	Tips []*Tip
+}
+
+// AddTip adds a new DAG tip to the message.
+func (msg *MsgTips) AddTip(hash *chainhash.Hash, height int32) error {
+	if len(msg.Tips)+1 > MaxTipsPerMsg {
+		str := fmt.Sprintf("too many tips for message [max %v]",
+			MaxTipsPerMsg)
+		return messageError("MsgTips.AddTip", str)
+	}
+
+	msg.Tips = append(msg.Tips, &Tip{Hash: *hash, Height: height})
+	return nil
+}
+
+// SotoDecode decodes r using the soterd protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgTips) SotoDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxTipsPerMsg {
+		str := fmt.Sprintf("too many tips for message [count %v, max %v]",
+			count, MaxTipsPerMsg)
+		return messageError("MsgTips.SotoDecode", str)
+	}
+
+	msg.Tips = make([]*Tip, 0, count)
+	for i := uint64(0); i < count; i++ {
+		tip := new(Tip)
+		if err := readElement(r, &tip.Hash); err != nil {
+			return err
+		}
+		if err := readElement(r, &tip.Height); err != nil {
+			return err
+		}
+		msg.Tips = append(msg.Tips, tip)
+	}
+
+	return nil
+}
+
+// SotoEncode encodes the receiver to w using the soterd protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgTips) SotoEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	count := len(msg.Tips)
+	if count > MaxTipsPerMsg {
+		str := fmt.Sprintf("too many tips for message [count %v, max %v]",
+			count, MaxTipsPerMsg)
+		return messageError("MsgTips.SotoEncode", str)
+	}
+
+	if err := WriteVarInt(w, pver, uint64(count)); err != nil {
+		return err
+	}
+
+	for _, tip := range msg.Tips {
+		if err := writeElement(w, &tip.Hash); err != nil {
+			return err
+		}
+		if err := writeElement(w, tip.Height); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is
+// part of the Message interface implementation.
+func (msg *MsgTips) Command() string {
+	return CmdTips
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgTips) MaxPayloadLength(pver uint32) uint32 {
+	// Num tips (varInt) + max allowed tips, each a hash plus a height.
+	return uint32(MaxVarIntPayload + MaxTipsPerMsg*(chainhash.HashSize+4))
+}
+
+// NewMsgTips returns a new soterd tips message that conforms to the Message
+// interface.  See MsgTips for details.
+func NewMsgTips() *MsgTips {
+	return &MsgTips{
+		Tips: make([]*Tip, 0, MaxTipsPerMsg),
+	}
+}
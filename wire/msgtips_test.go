@@ -0,0 +1,231 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/soteria-dag/soterd/chaincfg/chainhash"
+)
+
+// TestTips tests the MsgTips API.
+func TestTips(t *testing.T) {
+	pver := ProtocolVersion
+
+	// Block 99500 hash.
+	hashStr := "000000000002e7ad7b9eef9479e4aabc65cb831269cc20d2632c13684406dee0"
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	height := int32(99500)
+
+	// Ensure the command is expected value.
+	wantCmd := "tips"
+	msg := NewMsgTips()
+	if cmd := msg.Command(); cmd != wantCmd {
+		t.Errorf("NewMsgTips: wrong command - got %v want %v",
+			cmd, wantCmd)
+	}
+
+	// Ensure max payload is expected value for latest protocol version.
+	// Num tips (varInt) + max allowed tips, each a hash plus a height.
+	wantPayload := uint32(9 + MaxTipsPerMsg*(chainhash.HashSize+4))
+	maxPayload := msg.MaxPayloadLength(pver)
+	if maxPayload != wantPayload {
+		t.Errorf("MaxPayloadLength: wrong max payload length for "+
+			"protocol version %d - got %v, want %v", pver,
+			maxPayload, wantPayload)
+	}
+
+	// Ensure tips are added properly.
+	err = msg.AddTip(hash, height)
+	if err != nil {
+		t.Errorf("AddTip: %v", err)
+	}
+	if !msg.Tips[0].Hash.IsEqual(hash) || msg.Tips[0].Height != height {
+		t.Errorf("AddTip: wrong tip added - got %v, want (%v, %v)",
+			spew.Sprint(msg.Tips[0]), hash, height)
+	}
+
+	// Ensure adding more than the max allowed tips per message returns an
+	// error.
+	for i := 0; i < MaxTipsPerMsg; i++ {
+		err = msg.AddTip(hash, height)
+	}
+	if err == nil {
+		t.Errorf("AddTip: expected error on too many tips added")
+	}
+}
+
+// TestTipsWire tests the MsgTips wire encode and decode for various numbers
+// of tips and protocol versions.
+func TestTipsWire(t *testing.T) {
+	pver := uint32(60002)
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	height := int32(99499)
+
+	// MsgTips message with no tips.
+	noTips := NewMsgTips()
+	noTipsEncoded := []byte{
+		0x00, // Varint for number of tips
+	}
+
+	// MsgTips message with one tip.
+	oneTip := NewMsgTips()
+	if err := oneTip.AddTip(hash, height); err != nil {
+		t.Errorf("AddTip: %v", err)
+	}
+	oneTipEncoded := []byte{
+		0x01, // Varint for number of tips
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // hash
+		0xab, 0x84, 0x01, 0x00, // height
+	}
+
+	tests := []struct {
+		in   *MsgTips        // Message to encode
+		out  *MsgTips        // Expected decoded message
+		buf  []byte          // Wire encoding
+		pver uint32          // Protocol version for wire encoding
+		enc  MessageEncoding // Message encoding format
+	}{
+		{noTips, noTips, noTipsEncoded, pver, BaseEncoding},
+		{oneTip, oneTip, oneTipEncoded, pver, BaseEncoding},
+		{noTips, noTips, noTipsEncoded, ProtocolVersion, BaseEncoding},
+		{oneTip, oneTip, oneTipEncoded, ProtocolVersion, BaseEncoding},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		var buf bytes.Buffer
+		err := test.in.SotoEncode(&buf, test.pver, test.enc)
+		if err != nil {
+			t.Errorf("SotoEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("SotoEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+
+		var msg MsgTips
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.SotoDecode(rbuf, test.pver, test.enc)
+		if err != nil {
+			t.Errorf("SotoDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(&msg, test.out) {
+			t.Errorf("SotoDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(&msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestTipsWireErrors performs negative tests against wire encode and decode
+// of MsgTips to confirm error paths work correctly.
+func TestTipsWireErrors(t *testing.T) {
+	pver := uint32(60002)
+	wireErr := &MessageError{}
+
+	hashStr := "2710f40c87ec93d010a6fd95f42c59a2cbacc60b18cf6b7957535"
+	hash, err := chainhash.NewHashFromStr(hashStr)
+	if err != nil {
+		t.Errorf("NewHashFromStr: %v", err)
+	}
+	height := int32(99499)
+
+	baseTips := NewMsgTips()
+	if err := baseTips.AddTip(hash, height); err != nil {
+		t.Errorf("AddTip: %v", err)
+	}
+	baseTipsEncoded := []byte{
+		0x01, // Varint for number of tips
+		0x35, 0x75, 0x95, 0xb7, 0xf6, 0x8c, 0xb1, 0x60,
+		0xcc, 0xba, 0x2c, 0x9a, 0xc5, 0x42, 0x5f, 0xd9,
+		0x6f, 0x0a, 0x01, 0x3d, 0xc9, 0x7e, 0xc8, 0x40,
+		0x0f, 0x71, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, // hash
+		0xab, 0x84, 0x01, 0x00, // height
+	}
+
+	// Message that forces an error by having more than the max allowed
+	// tips.
+	maxTips := NewMsgTips()
+	for i := 0; i < MaxTipsPerMsg; i++ {
+		_ = maxTips.AddTip(hash, height)
+	}
+	maxTips.Tips = append(maxTips.Tips, &Tip{Hash: *hash, Height: height})
+	maxTipsEncoded := []byte{
+		0xfd, 0xe9, 0x03, // Varint for number of tips (1001)
+	}
+
+	tests := []struct {
+		in       *MsgTips        // Value to encode
+		buf      []byte          // Wire encoding
+		pver     uint32          // Protocol version for wire encoding
+		enc      MessageEncoding // Message encoding format
+		max      int             // Max size of fixed buffer to induce errors
+		writeErr error           // Expected write error
+		readErr  error           // Expected read error
+	}{
+		// Force error in tip count.
+		{baseTips, baseTipsEncoded, pver, BaseEncoding, 0, io.ErrShortWrite, io.EOF},
+		// Force error in tip hash.
+		{baseTips, baseTipsEncoded, pver, BaseEncoding, 1, io.ErrShortWrite, io.EOF},
+		// Force error in tip height.
+		{baseTips, baseTipsEncoded, pver, BaseEncoding, 33, io.ErrShortWrite, io.EOF},
+		// Force error with greater than max tips.
+		{maxTips, maxTipsEncoded, pver, BaseEncoding, 3, wireErr, wireErr},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		w := newFixedWriter(test.max)
+		err := test.in.SotoEncode(w, test.pver, test.enc)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.writeErr) {
+			t.Errorf("SotoEncode #%d wrong error got: %v, want: %v",
+				i, err, test.writeErr)
+			continue
+		}
+		if _, ok := err.(*MessageError); !ok {
+			if err != test.writeErr {
+				t.Errorf("SotoEncode #%d wrong error got: %v, "+
+					"want: %v", i, err, test.writeErr)
+				continue
+			}
+		}
+
+		var msg MsgTips
+		r := newFixedReader(test.max, test.buf)
+		err = msg.SotoDecode(r, test.pver, test.enc)
+		if reflect.TypeOf(err) != reflect.TypeOf(test.readErr) {
+			t.Errorf("SotoDecode #%d wrong error got: %v, want: %v",
+				i, err, test.readErr)
+			continue
+		}
+		if _, ok := err.(*MessageError); !ok {
+			if err != test.readErr {
+				t.Errorf("SotoDecode #%d wrong error got: %v, "+
+					"want: %v", i, err, test.readErr)
+				continue
+			}
+		}
+	}
+}
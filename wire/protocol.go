@@ -0,0 +1,56 @@
+// Copyright (c) 2013-2016 The btcsuite developers
+// Copyright (c) 2018-2019 The Soteria DAG developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import "strconv"
+
+// ProtocolVersion is the latest protocol version this package supports.
+const ProtocolVersion uint32 = BlockLocatorHashVersion
+
+// Bitcoin-era protocol versions that soterd inherits from btcd, and which
+// MsgGetHeaders/MsgGetBlocks wire tests exercise to make sure older peers are
+// still handled correctly.
+const (
+	// BIP0031Version is the protocol version BIP0031 was introduced in.
+	BIP0031Version uint32 = 60000
+
+	// NetAddressTimeVersion is the protocol version which added a
+	// timestamp field to the NetAddress type.
+	NetAddressTimeVersion uint32 = 31402
+
+	// BIP0035Version is the protocol version BIP0035 was introduced in.
+	BIP0035Version uint32 = 60002
+
+	// MultipleAddressVersion is the protocol version which allowed
+	// multiple addresses per message.
+	MultipleAddressVersion uint32 = 209
+)
+
+// MaxBlockLocatorsPerMsg is the maximum number of block locator heights and
+// hashes allowed per message.
+const MaxBlockLocatorsPerMsg = 500
+
+// BlockLocatorHashVersion is the protocol version which added
+// BlockLocatorHashes to MsgGetHeaders/MsgGetBlocks. In a DAG, a height alone
+// doesn't identify a unique block, so peers negotiating this version or
+// later also exchange the block hashes paired with each locator height,
+// letting the remote side resolve the unknown frontier unambiguously.
+const BlockLocatorHashVersion uint32 = 70015
+
+// ServiceFlag identifies services supported by a soterd peer.
+type ServiceFlag uint64
+
+// MessageEncoding represents the wire message encoding format to be used.
+type MessageEncoding uint32
+
+// BaseEncoding encodes all messages in the default format specified for the
+// soterd wire protocol.
+const BaseEncoding MessageEncoding = 1 << iota
+
+// String returns the ServiceFlag in human-readable form.
+func (f ServiceFlag) String() string {
+	return "ServiceFlag(" + strconv.FormatUint(uint64(f), 10) + ")"
+}